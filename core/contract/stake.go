@@ -0,0 +1,12 @@
+package contract
+
+import (
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+// CreateStakeContractByCode derives the stake address a DPoS-v2 vote
+// program commits to from its redeem script, reusing the same
+// code-to-program-hash derivation standard and multi-sign contracts use.
+func CreateStakeContractByCode(code []byte) (Uint168, error) {
+	return ToCodeHash(code)
+}
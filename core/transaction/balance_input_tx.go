@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"errors"
+	"io"
+
+	"github.com/elastos/Elastos.ELA.Utility/common/serialization"
+)
+
+// BalanceInputTxType is the envelope type byte for BalanceInputTx. It
+// lives above the legacy TransactionType range so a single leading byte
+// lets MarshalBinary/UnmarshalBinary tell a typed envelope apart from a
+// legacy transaction.
+const BalanceInputTxType byte = 0xf3
+
+func init() {
+	RegisterTxInner(BalanceInputTxType, func() TxInner { return new(BalanceInputTx) })
+}
+
+// BalanceInputTx is the typed-envelope transaction shape that spends only
+// account-model BalanceInputs, with none of the legacy fields (Payload,
+// Attributes, UTXOInputs, Programs) a legacy Transaction always carries.
+type BalanceInputTx struct {
+	BalanceInputs []*BalanceTxInput
+	Outputs       []*TxOutput
+	LockTime      uint32
+}
+
+// NewBalanceInputTx builds a Transaction around a BalanceInputTx, the way
+// NewTx is meant to be used for typed transaction shapes.
+func NewBalanceInputTx(inputs []*BalanceTxInput, outputs []*TxOutput, lockTime uint32) *Transaction {
+	return NewTx(&BalanceInputTx{
+		BalanceInputs: inputs,
+		Outputs:       outputs,
+		LockTime:      lockTime,
+	})
+}
+
+func (t *BalanceInputTx) copy() TxInner {
+	cp := *t
+	return &cp
+}
+
+func (t *BalanceInputTx) txType() byte {
+	return BalanceInputTxType
+}
+
+func (t *BalanceInputTx) encode(w io.Writer) error {
+	if err := serialization.WriteVarUint(w, uint64(len(t.BalanceInputs))); err != nil {
+		return errors.New("BalanceInputTx BalanceInputs length encode failed.")
+	}
+	for _, input := range t.BalanceInputs {
+		if err := input.Serialize(w); err != nil {
+			return err
+		}
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(t.Outputs))); err != nil {
+		return errors.New("BalanceInputTx Outputs length encode failed.")
+	}
+	for _, output := range t.Outputs {
+		if err := output.Serialize(w); err != nil {
+			return err
+		}
+	}
+	if err := serialization.WriteUint32(w, t.LockTime); err != nil {
+		return errors.New("BalanceInputTx LockTime encode failed.")
+	}
+	return nil
+}
+
+func (t *BalanceInputTx) decode(r io.Reader) error {
+	count, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("BalanceInputTx BalanceInputs length decode failed.")
+	}
+	if count > 0 {
+		for i := uint64(0); i < count; i++ {
+			input := new(BalanceTxInput)
+			if err := input.Deserialize(r); err != nil {
+				return err
+			}
+			t.BalanceInputs = append(t.BalanceInputs, input)
+		}
+	}
+
+	count, err = serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("BalanceInputTx Outputs length decode failed.")
+	}
+	if count > 0 {
+		for i := uint64(0); i < count; i++ {
+			output := new(TxOutput)
+			if err := output.Deserialize(r); err != nil {
+				return err
+			}
+			t.Outputs = append(t.Outputs, output)
+		}
+	}
+
+	temp, err := serialization.ReadUint32(r)
+	if err != nil {
+		return errors.New("BalanceInputTx LockTime decode failed.")
+	}
+	t.LockTime = uint32(temp)
+	return nil
+}
@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+func TestBalanceInputTxMarshalUnmarshalBinary(t *testing.T) {
+	tx := NewBalanceInputTx(
+		[]*BalanceTxInput{
+			{AssetID: Uint256{1}, Value: 10, ProgramHash: Uint168{2}, Nonce: 1},
+		},
+		[]*TxOutput{
+			{AssetID: Uint256{1}, Value: 10, ProgramHash: Uint168{3}},
+		},
+		42,
+	)
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if data[0] != BalanceInputTxType {
+		t.Fatalf("MarshalBinary leading byte = 0x%x, want 0x%x", data[0], BalanceInputTxType)
+	}
+
+	decoded := new(Transaction)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	inner, ok := decoded.inner.(*BalanceInputTx)
+	if !ok {
+		t.Fatalf("decoded.inner is %T, want *BalanceInputTx", decoded.inner)
+	}
+	if len(inner.BalanceInputs) != 1 || inner.BalanceInputs[0].Nonce != 1 {
+		t.Errorf("BalanceInputs round trip failed: %+v", inner.BalanceInputs)
+	}
+	if len(inner.Outputs) != 1 || inner.Outputs[0].ProgramHash != (Uint168{3}) {
+		t.Errorf("Outputs round trip failed: %+v", inner.Outputs)
+	}
+	if inner.LockTime != 42 {
+		t.Errorf("LockTime = %d, want 42", inner.LockTime)
+	}
+
+	if tx.Hash() != decoded.Hash() {
+		t.Errorf("Hash mismatch after MarshalBinary/UnmarshalBinary round trip")
+	}
+}
+
+func TestBalanceInputTxStringAndGetSize(t *testing.T) {
+	tx := NewBalanceInputTx(
+		[]*BalanceTxInput{
+			{AssetID: Uint256{1}, Value: 10, ProgramHash: Uint168{2}, Nonce: 1},
+		},
+		[]*TxOutput{
+			{AssetID: Uint256{1}, Value: 10, ProgramHash: Uint168{3}},
+		},
+		42,
+	)
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if size := tx.GetSize(); size != len(data) {
+		t.Errorf("GetSize() = %d, want %d (len of MarshalBinary output)", size, len(data))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("String() panicked on a typed transaction: %v", r)
+		}
+	}()
+	if s := tx.String(); s == "" {
+		t.Errorf("String() returned an empty string")
+	}
+}
+
+func TestTransactionUnmarshalBinaryLegacyPath(t *testing.T) {
+	legacy := &Transaction{
+		TxType: CreateNFTTxType,
+		Payload: &CreateNFT{
+			ReferKey:         Uint256{1},
+			StakeAddress:     Uint168{2},
+			GenesisBlockHash: Uint256{3},
+		},
+	}
+
+	data, err := legacy.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	decoded := new(Transaction)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if decoded.inner != nil {
+		t.Fatalf("decoded.inner = %+v, want nil for a legacy transaction", decoded.inner)
+	}
+	if decoded.TxType != CreateNFTTxType {
+		t.Errorf("TxType = %v, want %v", decoded.TxType, CreateNFTTxType)
+	}
+}
@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/common/serialization"
+)
+
+// BalanceTxInput is an account-model input: it debits Value of AssetID
+// directly from ProgramHash's balance instead of consuming a UTXO, so a
+// transaction can mix UTXO inputs and balance inputs side by side. Nonce
+// keeps two debits of the same amount from being replayed as one.
+type BalanceTxInput struct {
+	AssetID     Uint256
+	Value       Fixed64
+	ProgramHash Uint168
+	Nonce       uint64
+}
+
+func (bi *BalanceTxInput) Serialize(w io.Writer) error {
+	if _, err := bi.AssetID.Serialize(w); err != nil {
+		return errors.New("BalanceTxInput AssetID Serialize failed.")
+	}
+	if _, err := bi.Value.Serialize(w); err != nil {
+		return errors.New("BalanceTxInput Value Serialize failed.")
+	}
+	if _, err := bi.ProgramHash.Serialize(w); err != nil {
+		return errors.New("BalanceTxInput ProgramHash Serialize failed.")
+	}
+	if err := serialization.WriteUint64(w, bi.Nonce); err != nil {
+		return errors.New("BalanceTxInput Nonce Serialize failed.")
+	}
+	return nil
+}
+
+func (bi *BalanceTxInput) Deserialize(r io.Reader) error {
+	if err := bi.AssetID.Deserialize(r); err != nil {
+		return errors.New("BalanceTxInput AssetID Deserialize failed.")
+	}
+	if err := bi.Value.Deserialize(r); err != nil {
+		return errors.New("BalanceTxInput Value Deserialize failed.")
+	}
+	if err := bi.ProgramHash.Deserialize(r); err != nil {
+		return errors.New("BalanceTxInput ProgramHash Deserialize failed.")
+	}
+	nonce, err := serialization.ReadUint64(r)
+	if err != nil {
+		return errors.New("BalanceTxInput Nonce Deserialize failed.")
+	}
+	bi.Nonce = nonce
+	return nil
+}
@@ -0,0 +1,182 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/common/serialization"
+)
+
+// TransferCrossChainAssetTxType is the TransactionType for a
+// TransferCrossChainAsset transaction.
+const TransferCrossChainAssetTxType TransactionType = 0x0c
+
+func init() {
+	RegisterPayload(TransferCrossChainAssetTxType, func() Payload { return new(TransferCrossChainAsset) })
+}
+
+// IsCrossChainTx reports whether tx moves assets to a side-chain.
+func (tx *Transaction) IsCrossChainTx() bool {
+	return tx.TxType == TransferCrossChainAssetTxType
+}
+
+// GetCrossChainArbitrators returns the bare public keys the existing
+// multi-sign parser extracts from tx's redeem script, provided that
+// script's final opcode is CROSSCHAIN. GetMultiSignPublicKeys' entries
+// still carry their leading 0x40 push byte (see LegacySigner.Sender),
+// which is stripped here so the result matches the bare public keys a
+// CrossChainConfig.Arbitrators registry is populated with.
+func (tx *Transaction) GetCrossChainArbitrators() ([][]byte, error) {
+	code, err := tx.GetTransactionCode()
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 || code[len(code)-1] != CROSSCHAIN {
+		return nil, errors.New("GetCrossChainArbitrators: redeem script does not end in CROSSCHAIN")
+	}
+	keys, err := tx.GetMultiSignPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	arbitrators := make([][]byte, len(keys))
+	for i, key := range keys {
+		arbitrators[i] = key[1:]
+	}
+	return arbitrators, nil
+}
+
+// TransferCrossChainAsset moves tx.Outputs[OutputIndexes[i]] to the
+// side-chain address CrossChainAddresses[i], locking CrossChainAmounts[i]
+// of it there. The three slices are parallel and must be the same length.
+type TransferCrossChainAsset struct {
+	CrossChainAddresses []string
+	OutputIndexes       []uint64
+	CrossChainAmounts   []Fixed64
+}
+
+func (p *TransferCrossChainAsset) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	p.Serialize(buf, version)
+	return buf.Bytes()
+}
+
+func (p *TransferCrossChainAsset) Serialize(w io.Writer, version byte) error {
+	if len(p.CrossChainAddresses) != len(p.OutputIndexes) || len(p.CrossChainAddresses) != len(p.CrossChainAmounts) {
+		return errors.New("TransferCrossChainAsset Serialize failed: address/index/amount counts don't match")
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(p.CrossChainAddresses))); err != nil {
+		return errors.New("TransferCrossChainAsset address count Serialize failed.")
+	}
+	for i, address := range p.CrossChainAddresses {
+		if err := serialization.WriteVarUint(w, uint64(len(address))); err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAddresses Serialize failed.")
+		}
+		if _, err := w.Write([]byte(address)); err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAddresses Serialize failed.")
+		}
+		if err := serialization.WriteUint64(w, p.OutputIndexes[i]); err != nil {
+			return errors.New("TransferCrossChainAsset OutputIndexes Serialize failed.")
+		}
+		if _, err := p.CrossChainAmounts[i].Serialize(w); err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAmounts Serialize failed.")
+		}
+	}
+	return nil
+}
+
+func (p *TransferCrossChainAsset) Deserialize(r io.Reader, version byte) error {
+	count, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return errors.New("TransferCrossChainAsset address count Deserialize failed.")
+	}
+	for i := uint64(0); i < count; i++ {
+		addressLen, err := serialization.ReadVarUint(r, 0)
+		if err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAddresses Deserialize failed.")
+		}
+		address := make([]byte, addressLen)
+		if _, err := io.ReadFull(r, address); err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAddresses Deserialize failed.")
+		}
+		p.CrossChainAddresses = append(p.CrossChainAddresses, string(address))
+
+		outputIndex, err := serialization.ReadUint64(r)
+		if err != nil {
+			return errors.New("TransferCrossChainAsset OutputIndexes Deserialize failed.")
+		}
+		p.OutputIndexes = append(p.OutputIndexes, outputIndex)
+
+		var amount Fixed64
+		if err := amount.Deserialize(r); err != nil {
+			return errors.New("TransferCrossChainAsset CrossChainAmounts Deserialize failed.")
+		}
+		p.CrossChainAmounts = append(p.CrossChainAmounts, amount)
+	}
+	return nil
+}
+
+// CrossChainConfig is the arbitrator registry a TransferCrossChainAsset
+// transaction's signer set is checked against.
+type CrossChainConfig struct {
+	// Arbitrators holds the public keys of the current arbitrator set.
+	Arbitrators [][]byte
+}
+
+// VerifyTransferCrossChainAsset checks that every referenced output index
+// is in range, every cross-chain amount fits inside its output, the
+// redeem script's signers are all registered arbitrators, and that at
+// least ceil(2/3) of cfg.Arbitrators actually signed (by attached
+// signature count, not by how many public keys the redeem script names).
+func VerifyTransferCrossChainAsset(tx *Transaction, cfg *CrossChainConfig) error {
+	if cfg == nil {
+		return errors.New("VerifyTransferCrossChainAsset: cfg is required")
+	}
+	payload, ok := tx.Payload.(*TransferCrossChainAsset)
+	if !ok {
+		return errors.New("VerifyTransferCrossChainAsset: not a TransferCrossChainAsset transaction")
+	}
+	if len(payload.CrossChainAddresses) != len(payload.OutputIndexes) ||
+		len(payload.CrossChainAddresses) != len(payload.CrossChainAmounts) {
+		return errors.New("VerifyTransferCrossChainAsset: address/index/amount counts don't match")
+	}
+	claimed := make(map[uint64]Fixed64, len(payload.OutputIndexes))
+	for i, idx := range payload.OutputIndexes {
+		if idx >= uint64(len(tx.Outputs)) {
+			return errors.New("VerifyTransferCrossChainAsset: output index out of range")
+		}
+		total := claimed[idx] + payload.CrossChainAmounts[i]
+		if total > tx.Outputs[idx].Value {
+			return errors.New("VerifyTransferCrossChainAsset: cross-chain amount exceeds output value")
+		}
+		claimed[idx] = total
+	}
+
+	signers, err := tx.GetCrossChainArbitrators()
+	if err != nil {
+		return err
+	}
+	registry := make(map[string]bool, len(cfg.Arbitrators))
+	for _, arbitrator := range cfg.Arbitrators {
+		registry[string(arbitrator)] = true
+	}
+	for _, signer := range signers {
+		if !registry[string(signer)] {
+			return errors.New("VerifyTransferCrossChainAsset: signer is not a registered arbitrator")
+		}
+	}
+	// Arbitrator public keys are public information, so naming enough of
+	// them in the redeem script proves nothing about who actually signed.
+	// Count the signatures attached to Programs[0].Parameter instead.
+	signedCount, err := tx.GetSignedCount()
+	if err != nil {
+		return err
+	}
+	// ceil(2/3 * len(cfg.Arbitrators))
+	required := (len(cfg.Arbitrators)*2 + 2) / 3
+	if signedCount < required {
+		return errors.New("VerifyTransferCrossChainAsset: not enough arbitrators signed")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package transaction
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/core/contract/program"
+	. "github.com/elastos/Elastos.ELA.Utility/core/signature"
+)
+
+func TestVerifyTransferCrossChainAsset(t *testing.T) {
+	pk1 := bytes.Repeat([]byte{0x01}, 33)
+	pk2 := bytes.Repeat([]byte{0x02}, 33)
+	pk3 := bytes.Repeat([]byte{0x03}, 33)
+	code := buildMultiSignCode(2, 3, [][]byte{pk1, pk2, pk3}, CROSSCHAIN)
+
+	cfg := &CrossChainConfig{
+		Arbitrators: [][]byte{pk1, pk2, pk3},
+	}
+
+	newTx := func(parameter []byte) *Transaction {
+		return &Transaction{
+			TxType: TransferCrossChainAssetTxType,
+			Payload: &TransferCrossChainAsset{
+				CrossChainAddresses: []string{"8VYXVxKKSAxkmRrfmGpQR2zT5LpiZgxMrFzTw"},
+				OutputIndexes:       []uint64{0},
+				CrossChainAmounts:   []Fixed64{5},
+			},
+			Outputs:  []*TxOutput{{AssetID: Uint256{1}, Value: 10}},
+			Programs: []*program.Program{{Code: code, Parameter: parameter}},
+		}
+	}
+
+	if err := VerifyTransferCrossChainAsset(newTx(nil), nil); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset with nil cfg should fail, not panic on cfg.Arbitrators")
+	}
+
+	// Regression: all three arbitrators are named in the redeem script, but
+	// none of them actually signed (no attached signatures) -- counting
+	// redeem-script keys instead of signatures would have passed this.
+	if err := VerifyTransferCrossChainAsset(newTx(nil), cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject a quorum with no attached signatures")
+	}
+
+	quorumSigs := make([]byte, SignatureScriptLength*2)
+	if err := VerifyTransferCrossChainAsset(newTx(quorumSigs), cfg); err != nil {
+		t.Errorf("VerifyTransferCrossChainAsset should accept ceil(2/3) attached signatures: %v", err)
+	}
+
+	belowQuorumSigs := make([]byte, SignatureScriptLength)
+	if err := VerifyTransferCrossChainAsset(newTx(belowQuorumSigs), cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject fewer than ceil(2/3) attached signatures")
+	}
+
+	outOfRange := newTx(quorumSigs)
+	outOfRange.Payload.(*TransferCrossChainAsset).OutputIndexes = []uint64{5}
+	if err := VerifyTransferCrossChainAsset(outOfRange, cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject an out-of-range output index")
+	}
+
+	tooMuch := newTx(quorumSigs)
+	tooMuch.Payload.(*TransferCrossChainAsset).CrossChainAmounts = []Fixed64{50}
+	if err := VerifyTransferCrossChainAsset(tooMuch, cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject an amount exceeding the output value")
+	}
+
+	untrusted := newTx(quorumSigs)
+	untrusted.Programs[0].Code = buildMultiSignCode(2, 3, [][]byte{pk1, pk2, bytes.Repeat([]byte{0x99}, 33)}, CROSSCHAIN)
+	if err := VerifyTransferCrossChainAsset(untrusted, cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject a signer that isn't a registered arbitrator")
+	}
+
+	// Regression: repeating the same output index must not let its value
+	// be claimed twice -- each duplicate has to fit what's left, not the
+	// output's full value again.
+	doubleClaim := newTx(quorumSigs)
+	doubleClaim.Payload.(*TransferCrossChainAsset).OutputIndexes = []uint64{0, 0}
+	doubleClaim.Payload.(*TransferCrossChainAsset).CrossChainAmounts = []Fixed64{10, 10}
+	doubleClaim.Payload.(*TransferCrossChainAsset).CrossChainAddresses = []string{
+		"8VYXVxKKSAxkmRrfmGpQR2zT5LpiZgxMrFzTw", "8VYXVxKKSAxkmRrfmGpQR2zT5LpiZgxMrFzTw",
+	}
+	if err := VerifyTransferCrossChainAsset(doubleClaim, cfg); err == nil {
+		t.Errorf("VerifyTransferCrossChainAsset should reject claiming the same output's value twice")
+	}
+}
@@ -0,0 +1,184 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/core/contract"
+)
+
+// TransactionType values for the NFT payload types.
+const (
+	CreateNFTTxType   TransactionType = 0x0a
+	TransferNFTTxType TransactionType = 0x0b
+)
+
+func init() {
+	RegisterPayload(CreateNFTTxType, func() Payload { return new(CreateNFT) })
+	RegisterPayload(TransferNFTTxType, func() Payload { return new(TransferNFT) })
+}
+
+// IsNFTTx reports whether tx creates or transfers an NFT.
+func (tx *Transaction) IsNFTTx() bool {
+	return tx.TxType == CreateNFTTxType || tx.TxType == TransferNFTTxType
+}
+
+// CreateNFT mints an NFT bound to a DPoS-v2 vote: ReferKey names the vote
+// being staked against, StakeAddress is the address that must match the
+// redeem script signing this transaction, and GenesisBlockHash pins the
+// chain the vote was cast on.
+type CreateNFT struct {
+	ReferKey         Uint256
+	StakeAddress     Uint168
+	GenesisBlockHash Uint256
+}
+
+func (p *CreateNFT) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	p.Serialize(buf, version)
+	return buf.Bytes()
+}
+
+func (p *CreateNFT) Serialize(w io.Writer, version byte) error {
+	if _, err := p.ReferKey.Serialize(w); err != nil {
+		return errors.New("CreateNFT ReferKey Serialize failed.")
+	}
+	if _, err := p.StakeAddress.Serialize(w); err != nil {
+		return errors.New("CreateNFT StakeAddress Serialize failed.")
+	}
+	if _, err := p.GenesisBlockHash.Serialize(w); err != nil {
+		return errors.New("CreateNFT GenesisBlockHash Serialize failed.")
+	}
+	return nil
+}
+
+func (p *CreateNFT) Deserialize(r io.Reader, version byte) error {
+	if err := p.ReferKey.Deserialize(r); err != nil {
+		return errors.New("CreateNFT ReferKey Deserialize failed.")
+	}
+	if err := p.StakeAddress.Deserialize(r); err != nil {
+		return errors.New("CreateNFT StakeAddress Deserialize failed.")
+	}
+	if err := p.GenesisBlockHash.Deserialize(r); err != nil {
+		return errors.New("CreateNFT GenesisBlockHash Deserialize failed.")
+	}
+	return nil
+}
+
+// TransferNFT spends an NFT: PrevNFTTxHash references the CreateNFT that
+// minted it or the TransferNFT that last spent it. The payload names no
+// new owner, and VerifyTransferNFT requires the same stake address as the
+// previous transaction, so the owning stake address does not change.
+type TransferNFT struct {
+	PrevNFTTxHash Uint256
+}
+
+func (p *TransferNFT) Data(version byte) []byte {
+	buf := new(bytes.Buffer)
+	p.Serialize(buf, version)
+	return buf.Bytes()
+}
+
+func (p *TransferNFT) Serialize(w io.Writer, version byte) error {
+	if _, err := p.PrevNFTTxHash.Serialize(w); err != nil {
+		return errors.New("TransferNFT PrevNFTTxHash Serialize failed.")
+	}
+	return nil
+}
+
+func (p *TransferNFT) Deserialize(r io.Reader, version byte) error {
+	if err := p.PrevNFTTxHash.Deserialize(r); err != nil {
+		return errors.New("TransferNFT PrevNFTTxHash Deserialize failed.")
+	}
+	return nil
+}
+
+// VerifyCreateNFT checks a CreateNFT transaction's stake binding: the
+// stake address derived from tx.Programs[0]'s redeem script must match the
+// payload's declared StakeAddress, and ReferKey must name an existing
+// DPoS-v2 vote. voteExists is injected, mirroring VerifyTransferNFT's
+// getTx, so this package doesn't need to depend on wherever votes are
+// stored; a nil voteExists is rejected rather than silently skipping the
+// check it implements.
+func VerifyCreateNFT(tx *Transaction, voteExists func(referKey Uint256) bool) error {
+	payload, ok := tx.Payload.(*CreateNFT)
+	if !ok {
+		return errors.New("VerifyCreateNFT: not a CreateNFT transaction")
+	}
+	if voteExists == nil {
+		return errors.New("VerifyCreateNFT: voteExists is required")
+	}
+	code, err := tx.GetTransactionCode()
+	if err != nil {
+		return err
+	}
+	stakeAddress, err := contract.CreateStakeContractByCode(code)
+	if err != nil {
+		return err
+	}
+	if stakeAddress != payload.StakeAddress {
+		return errors.New("VerifyCreateNFT: stake address does not match tx.Programs[0]")
+	}
+	if !voteExists(payload.ReferKey) {
+		return errors.New("VerifyCreateNFT: ReferKey does not reference an existing DPoS-v2 vote")
+	}
+	return nil
+}
+
+// nftOwnerStakeAddress returns the stake address the holder of an NFT must
+// sign with to transfer it on: the StakeAddress of the CreateNFT that
+// minted it, or, when prevTx is a TransferNFT, the stake address of that
+// transfer's redeem script (which VerifyTransferNFT required to match the
+// owner before it).
+func nftOwnerStakeAddress(prevTx *Transaction) (Uint168, error) {
+	switch payload := prevTx.Payload.(type) {
+	case *CreateNFT:
+		return payload.StakeAddress, nil
+	case *TransferNFT:
+		code, err := prevTx.GetTransactionCode()
+		if err != nil {
+			return Uint168{}, err
+		}
+		return contract.CreateStakeContractByCode(code)
+	default:
+		return Uint168{}, errors.New("VerifyTransferNFT: PrevNFTTxHash does not reference a CreateNFT or TransferNFT transaction")
+	}
+}
+
+// VerifyTransferNFT checks signature continuity: it fetches the
+// transaction payload.PrevNFTTxHash references via getTx (mirroring how
+// VerifyCreateNFT takes voteExists instead of reaching into storage
+// itself), derives that NFT's current owner, and requires tx's own redeem
+// script to resolve to the same stake address.
+func VerifyTransferNFT(tx *Transaction, getTx func(hash Uint256) (*Transaction, error)) error {
+	payload, ok := tx.Payload.(*TransferNFT)
+	if !ok {
+		return errors.New("VerifyTransferNFT: not a TransferNFT transaction")
+	}
+	if getTx == nil {
+		return errors.New("VerifyTransferNFT: getTx is required")
+	}
+	prevTx, err := getTx(payload.PrevNFTTxHash)
+	if err != nil {
+		return err
+	}
+	owner, err := nftOwnerStakeAddress(prevTx)
+	if err != nil {
+		return err
+	}
+
+	code, err := tx.GetTransactionCode()
+	if err != nil {
+		return err
+	}
+	signerAddress, err := contract.CreateStakeContractByCode(code)
+	if err != nil {
+		return err
+	}
+	if signerAddress != owner {
+		return errors.New("VerifyTransferNFT: signer does not match NFT owner")
+	}
+	return nil
+}
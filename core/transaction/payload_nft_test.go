@@ -0,0 +1,100 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/core/contract"
+	"github.com/elastos/Elastos.ELA.Utility/core/contract/program"
+)
+
+func standardCode(pubkey []byte) []byte {
+	code := append([]byte{0x21}, pubkey...)
+	return append(code, 0xac)
+}
+
+func TestVerifyCreateNFT(t *testing.T) {
+	code := standardCode(bytes.Repeat([]byte{0xAB}, 33))
+	stakeAddress, err := contract.CreateStakeContractByCode(code)
+	if err != nil {
+		t.Fatalf("CreateStakeContractByCode failed: %v", err)
+	}
+	referKey := Uint256{1}
+
+	tx := &Transaction{
+		TxType: CreateNFTTxType,
+		Payload: &CreateNFT{
+			ReferKey:     referKey,
+			StakeAddress: stakeAddress,
+		},
+		Programs: []*program.Program{{Code: code}},
+	}
+
+	if err := VerifyCreateNFT(tx, nil); err == nil {
+		t.Errorf("VerifyCreateNFT with nil voteExists should fail, not skip the check")
+	}
+	if err := VerifyCreateNFT(tx, func(Uint256) bool { return false }); err == nil {
+		t.Errorf("VerifyCreateNFT should reject a ReferKey with no matching vote")
+	}
+	if err := VerifyCreateNFT(tx, func(k Uint256) bool { return k == referKey }); err != nil {
+		t.Errorf("VerifyCreateNFT should accept a valid stake binding and vote: %v", err)
+	}
+
+	mismatched := &Transaction{
+		TxType: CreateNFTTxType,
+		Payload: &CreateNFT{
+			ReferKey:     referKey,
+			StakeAddress: Uint168{0xff},
+		},
+		Programs: []*program.Program{{Code: code}},
+	}
+	if err := VerifyCreateNFT(mismatched, func(Uint256) bool { return true }); err == nil {
+		t.Errorf("VerifyCreateNFT should reject a stake address that doesn't match Programs[0]")
+	}
+}
+
+func TestVerifyTransferNFT(t *testing.T) {
+	code := standardCode(bytes.Repeat([]byte{0xCD}, 33))
+	stakeAddress, err := contract.CreateStakeContractByCode(code)
+	if err != nil {
+		t.Fatalf("CreateStakeContractByCode failed: %v", err)
+	}
+
+	prevTxHash := Uint256{5}
+	createTx := &Transaction{
+		TxType:  CreateNFTTxType,
+		Payload: &CreateNFT{StakeAddress: stakeAddress},
+	}
+	getTx := func(hash Uint256) (*Transaction, error) {
+		if hash == prevTxHash {
+			return createTx, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	transferTx := &Transaction{
+		TxType:   TransferNFTTxType,
+		Payload:  &TransferNFT{PrevNFTTxHash: prevTxHash},
+		Programs: []*program.Program{{Code: code}},
+	}
+
+	if err := VerifyTransferNFT(transferTx, nil); err == nil {
+		t.Errorf("VerifyTransferNFT with nil getTx should fail, not skip the check")
+	}
+	if err := VerifyTransferNFT(transferTx, getTx); err != nil {
+		t.Errorf("VerifyTransferNFT should accept a transfer signed by the NFT's owner: %v", err)
+	}
+
+	wrongSigner := &Transaction{
+		TxType:  TransferNFTTxType,
+		Payload: &TransferNFT{PrevNFTTxHash: prevTxHash},
+		Programs: []*program.Program{
+			{Code: standardCode(bytes.Repeat([]byte{0xEE}, 33))},
+		},
+	}
+	if err := VerifyTransferNFT(wrongSigner, getTx); err == nil {
+		t.Errorf("VerifyTransferNFT should reject a transfer signed by someone other than the NFT's owner")
+	}
+}
@@ -0,0 +1,28 @@
+package transaction
+
+import "errors"
+
+// payloadFactories maps a TransactionType to the constructor for its empty
+// Payload, so new payload types (CreateNFT, TransferCrossChainAsset, ...)
+// register themselves instead of PayloadFactory growing a type switch.
+var payloadFactories = map[TransactionType]func() Payload{}
+
+// RegisterPayload registers the Payload constructor used to build an empty
+// Payload for txType before DeserializeUnsignedWithoutType reads into it.
+func RegisterPayload(txType TransactionType, factory func() Payload) {
+	payloadFactories[txType] = factory
+}
+
+// PayloadFactory builds an empty Payload for a given TransactionType.
+type PayloadFactory struct{}
+
+func (f *PayloadFactory) Create(txType TransactionType) (Payload, error) {
+	factory, ok := payloadFactories[txType]
+	if !ok {
+		return nil, errors.New("Transaction Payload Create: unsupported TxType")
+	}
+	return factory(), nil
+}
+
+// PayloadFactorySingleton is the PayloadFactory Transaction.DeserializeUnsignedWithoutType uses.
+var PayloadFactorySingleton = &PayloadFactory{}
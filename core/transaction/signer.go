@@ -0,0 +1,147 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+// Signer abstracts how a transaction's signing hash and signer public key
+// are derived, modelled on go-ethereum's types.Signer. It lives beside
+// Transaction rather than in core/signature to avoid an import cycle:
+// core/transaction already imports core/signature for opcode constants
+// such as MULTISIG and CROSSCHAIN.
+type Signer interface {
+	// Hash returns the hash a Program's signature is expected to cover.
+	Hash(tx *Transaction) Uint256
+
+	// Sender recovers the signer's public key from tx's redeem script.
+	Sender(tx *Transaction) ([]byte, error)
+
+	// SignatureValues returns the bytes to attach to tx's program for the
+	// given signature. LegacySigner and CrossChainSigner return sig
+	// unchanged and add no 0x40 push-length byte (see SignatureScriptLength).
+	SignatureValues(tx *Transaction, sig []byte) ([]byte, error)
+
+	// ChainID returns the chain this signer is bound to, or nil for
+	// signers that are not chain-specific.
+	ChainID() *big.Int
+}
+
+// LegacySigner reproduces the double-SHA256 of SerializeUnsigned that
+// Transaction.Hash() has always used for legacy transactions.
+type LegacySigner struct{}
+
+func (LegacySigner) Hash(tx *Transaction) Uint256 {
+	return legacySignerHash(tx)
+}
+
+func (LegacySigner) Sender(tx *Transaction) ([]byte, error) {
+	if keys, err := tx.GetMultiSignPublicKeys(); err == nil {
+		if len(keys) == 0 {
+			return nil, errors.New("LegacySigner: no public keys in redeem script")
+		}
+		// keys[0] still carries GetMultiSignPublicKeys' leading 0x40 push
+		// byte; strip it so both branches return the bare 33-byte key.
+		return keys[0][1:], nil
+	}
+	// Not a multi-sign script: fall back to the standard
+	// 0x21 || pubkey(33) || OP_CHECKSIG(0xac) redeem script and slice out
+	// the public key rather than handing back the raw script bytes.
+	code, err := tx.GetTransactionCode()
+	if err != nil {
+		return nil, err
+	}
+	if len(code) != PublicKeyScriptLength {
+		return nil, errors.New("LegacySigner: redeem script is neither standard nor multi-sign")
+	}
+	return code[1 : PublicKeyScriptLength-1], nil
+}
+
+func (LegacySigner) SignatureValues(tx *Transaction, sig []byte) ([]byte, error) {
+	if len(sig) == 0 {
+		return nil, errors.New("LegacySigner: empty signature")
+	}
+	return sig, nil
+}
+
+func (LegacySigner) ChainID() *big.Int {
+	return nil
+}
+
+// crossChainDomain domain-separates cross-chain signing hashes from
+// legacy ones, so a cross-chain signature can never be replayed as a
+// legacy one and vice versa.
+var crossChainDomain = []byte("ELA-CROSSCHAIN")
+
+// CrossChainSigner hashes cross-chain transactions (TxType ==
+// TransferCrossChainAssetTxType, see signerForTx) under crossChainDomain.
+type CrossChainSigner struct {
+	chainID *big.Int
+}
+
+func (s CrossChainSigner) Hash(tx *Transaction) Uint256 {
+	buf := new(bytes.Buffer)
+	buf.Write(crossChainDomain)
+	tx.SerializeUnsigned(buf)
+	first := sha256.Sum256(buf.Bytes())
+	return Uint256(sha256.Sum256(first[:]))
+}
+
+func (s CrossChainSigner) Sender(tx *Transaction) ([]byte, error) {
+	keys, err := tx.GetMultiSignPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("CrossChainSigner: no public keys in redeem script")
+	}
+	// keys[0] still carries GetMultiSignPublicKeys' leading 0x40 push
+	// byte; strip it, matching LegacySigner.Sender's multi-sign branch.
+	return keys[0][1:], nil
+}
+
+func (s CrossChainSigner) SignatureValues(tx *Transaction, sig []byte) ([]byte, error) {
+	if len(sig) == 0 {
+		return nil, errors.New("CrossChainSigner: empty signature")
+	}
+	return sig, nil
+}
+
+func (s CrossChainSigner) ChainID() *big.Int {
+	return s.chainID
+}
+
+// SignerConfig selects which Signer LatestSigner returns.
+type SignerConfig struct {
+	ChainID *big.Int
+}
+
+// LatestSigner returns the Signer callers should use for new transactions
+// given cfg, so call sites don't need to know which signer is current.
+func LatestSigner(cfg *SignerConfig) Signer {
+	if cfg != nil && cfg.ChainID != nil {
+		return CrossChainSigner{chainID: cfg.ChainID}
+	}
+	return LegacySigner{}
+}
+
+// LatestSignerForChainID is LatestSigner for callers that only have a
+// chain ID on hand. A nil chainID selects LegacySigner.
+func LatestSignerForChainID(chainID *big.Int) Signer {
+	return LatestSigner(&SignerConfig{ChainID: chainID})
+}
+
+// signerForTx picks the Signer Transaction.Hash() hashes tx under:
+// CrossChainSigner for cross-chain transactions, so their hash is
+// domain-separated from a legacy hash of the same bytes, LegacySigner for
+// everything else.
+func signerForTx(tx *Transaction) Signer {
+	if tx.IsCrossChainTx() {
+		return CrossChainSigner{}
+	}
+	return LegacySigner{}
+}
@@ -0,0 +1,101 @@
+package transaction
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+	"github.com/elastos/Elastos.ELA.Utility/core/contract/program"
+	. "github.com/elastos/Elastos.ELA.Utility/core/signature"
+)
+
+// buildMultiSignCode builds an m-of-n multi-sign redeem script: m ||
+// (0x40 || pubkey) for each pubkey || n || finalOp.
+func buildMultiSignCode(m, n byte, pubkeys [][]byte, finalOp byte) []byte {
+	code := []byte{m}
+	for _, pk := range pubkeys {
+		code = append(code, pushKey(pk)...)
+	}
+	code = append(code, n, finalOp)
+	return code
+}
+
+// pushKey is the 34-byte push GetMultiSignPublicKeys extracts a key as:
+// a 0x40 push-length byte followed by the 33-byte public key.
+func pushKey(pubkey []byte) []byte {
+	return append([]byte{0x40}, pubkey...)
+}
+
+func TestLegacySignerSenderStandardScript(t *testing.T) {
+	pubkey := bytes.Repeat([]byte{0xAB}, 33)
+	code := append([]byte{0x21}, pubkey...)
+	code = append(code, 0xac) // OP_CHECKSIG
+
+	tx := &Transaction{Programs: []*program.Program{{Code: code}}}
+
+	got, err := (LegacySigner{}).Sender(tx)
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if !bytes.Equal(got, pubkey) {
+		t.Errorf("Sender = %x, want the bare public key %x, not the whole redeem script", got, pubkey)
+	}
+}
+
+func TestLegacySignerSenderMultiSignScript(t *testing.T) {
+	pk1 := bytes.Repeat([]byte{0x01}, 33)
+	pk2 := bytes.Repeat([]byte{0x02}, 33)
+	pk3 := bytes.Repeat([]byte{0x03}, 33)
+	code := buildMultiSignCode(2, 3, [][]byte{pk1, pk2, pk3}, MULTISIG)
+
+	tx := &Transaction{Programs: []*program.Program{{Code: code}}}
+
+	got, err := (LegacySigner{}).Sender(tx)
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if !bytes.Equal(got, pk1) {
+		t.Errorf("Sender = %x, want the bare first multi-sign public key %x, not the 0x40-prefixed push", got, pk1)
+	}
+}
+
+func TestCrossChainSignerSenderMultiSignScript(t *testing.T) {
+	pk1 := bytes.Repeat([]byte{0x01}, 33)
+	pk2 := bytes.Repeat([]byte{0x02}, 33)
+	pk3 := bytes.Repeat([]byte{0x03}, 33)
+	code := buildMultiSignCode(2, 3, [][]byte{pk1, pk2, pk3}, CROSSCHAIN)
+
+	tx := &Transaction{Programs: []*program.Program{{Code: code}}}
+
+	got, err := (CrossChainSigner{}).Sender(tx)
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if !bytes.Equal(got, pk1) {
+		t.Errorf("Sender = %x, want the bare first multi-sign public key %x, not the 0x40-prefixed push", got, pk1)
+	}
+}
+
+func TestCrossChainSignerHashIsDomainSeparatedFromLegacy(t *testing.T) {
+	tx := &Transaction{
+		TxType:  TransferCrossChainAssetTxType,
+		Payload: &TransferCrossChainAsset{},
+	}
+
+	legacyHash := legacySignerHash(tx)
+	crossChainHash := (CrossChainSigner{}).Hash(tx)
+
+	if legacyHash == crossChainHash {
+		t.Errorf("CrossChainSigner.Hash collided with the legacy hash of the same bytes")
+	}
+}
+
+func TestLatestSignerForChainID(t *testing.T) {
+	if _, ok := LatestSignerForChainID(nil).(LegacySigner); !ok {
+		t.Errorf("LatestSignerForChainID(nil) did not select LegacySigner")
+	}
+	if _, ok := LatestSignerForChainID(big.NewInt(2)).(CrossChainSigner); !ok {
+		t.Errorf("LatestSignerForChainID(chainID) did not select CrossChainSigner")
+	}
+}
@@ -48,11 +48,65 @@ type Transaction struct {
 	LockTime       uint32
 	Programs       []*program.Program
 
+	// Trimmed marks a tx that carries only its hash (and, for spent
+	// tracking, a spent-outputs bitmap) rather than a full payload. Block
+	// indexes and light clients use it to persist/sync compact tx records.
+	Trimmed bool
+
+	// inner holds a typed (EIP-2718-style) transaction shape such as
+	// BalanceInputTx. It is nil for legacy transactions, which keep using
+	// the fields above directly.
+	inner TxInner
+
 	hash *Uint256
 }
 
+// TxInner is implemented by a typed transaction shape registered with
+// RegisterTxInner, so new shapes (different input/output sets, new
+// fields) can be added as typed envelopes without modifying Transaction.
+type TxInner interface {
+	copy() TxInner
+	encode(w io.Writer) error
+	decode(r io.Reader) error
+	txType() byte
+}
+
+// txInnerFactories maps an envelope type byte to the constructor for its
+// TxInner, so UnmarshalBinary can decode a typed envelope without a
+// hard-coded switch over every registered type.
+var txInnerFactories = map[byte]func() TxInner{}
+
+// RegisterTxInner registers the TxInner constructor for a typed envelope
+// byte, letting new transaction shapes plug into MarshalBinary and
+// UnmarshalBinary without Transaction needing to know about them.
+func RegisterTxInner(typ byte, factory func() TxInner) {
+	txInnerFactories[typ] = factory
+}
+
+// NewTx builds a Transaction around a typed inner payload. Future
+// transaction shapes only need to implement TxInner; Transaction itself
+// never has to change.
+func NewTx(inner TxInner) *Transaction {
+	return &Transaction{inner: inner}
+}
+
+// NewTrimmedTransaction returns a Transaction carrying only its hash, for
+// block indexes and light clients that don't need the full payload.
+func NewTrimmedTransaction(hash Uint256) *Transaction {
+	return &Transaction{
+		Trimmed: true,
+		hash:    &hash,
+	}
+}
+
 func (tx *Transaction) String() string {
 	tx.Hash()
+	if tx.inner != nil {
+		return "Transaction: {\n\t" +
+			"Hash: " + tx.hash.String() + "\n\t" +
+			"TxType: " + fmt.Sprintf("typed(%d)", tx.inner.txType()) + "\n\t" +
+			"}\n"
+	}
 	return "Transaction: {\n\t" +
 		"Hash: " + tx.hash.String() + "\n\t" +
 		"TxType: " + tx.TxType.Name() + "\n\t" +
@@ -122,7 +176,19 @@ func (tx *Transaction) SerializeUnsigned(w io.Writer) error {
 			utxo.Serialize(w)
 		}
 	}
-	// TODO BalanceInputs
+	//[]*BalanceInputs
+	err = serialization.WriteVarUint(w, uint64(len(tx.BalanceInputs)))
+	if err != nil {
+		return errors.New("Transaction item BalanceInputs length serialization failed.")
+	}
+	if len(tx.BalanceInputs) > 0 {
+		for _, balanceInput := range tx.BalanceInputs {
+			err = balanceInput.Serialize(w)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	//[]*Outputs
 	err = serialization.WriteVarUint(w, uint64(len(tx.Outputs)))
 	if err != nil {
@@ -171,6 +237,62 @@ func (tx *Transaction) Deserialize(r io.Reader) error {
 	return nil
 }
 
+// SerializeTrimmed writes the compact tx record a block index persists:
+// TxType, the tx's Hash, and a spent-outputs bitmap, with none of the
+// payload or program data Serialize writes.
+func (tx *Transaction) SerializeTrimmed(w io.Writer, spentOutputs []bool) error {
+	w.Write([]byte{byte(tx.TxType)})
+	hash := tx.Hash()
+	if _, err := hash.Serialize(w); err != nil {
+		return errors.New("Transaction SerializeTrimmed Hash failed.")
+	}
+	if err := serialization.WriteVarUint(w, uint64(len(spentOutputs))); err != nil {
+		return errors.New("Transaction SerializeTrimmed spentOutputs length failed.")
+	}
+	for _, spent := range spentOutputs {
+		var b byte
+		if spent {
+			b = 1
+		}
+		if _, err := w.Write([]byte{b}); err != nil {
+			return errors.New("Transaction SerializeTrimmed spentOutputs failed.")
+		}
+	}
+	return nil
+}
+
+// DeserializeTrimmed reads a record written by SerializeTrimmed, returning
+// the decoded spent-outputs bitmap and leaving tx in Trimmed mode.
+func (tx *Transaction) DeserializeTrimmed(r io.Reader) ([]bool, error) {
+	var txType [1]byte
+	if _, err := io.ReadFull(r, txType[:]); err != nil {
+		return nil, err
+	}
+	tx.TxType = TransactionType(txType[0])
+
+	var hash Uint256
+	if err := hash.Deserialize(r); err != nil {
+		return nil, errors.New("Transaction DeserializeTrimmed Hash failed.")
+	}
+
+	lens, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return nil, errors.New("Transaction DeserializeTrimmed spentOutputs length failed.")
+	}
+	var spentOutputs []bool
+	for i := uint64(0); i < lens; i++ {
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, errors.New("Transaction DeserializeTrimmed spentOutputs failed.")
+		}
+		spentOutputs = append(spentOutputs, b[0] != 0)
+	}
+
+	tx.Trimmed = true
+	tx.hash = &hash
+	return spentOutputs, nil
+}
+
 func (tx *Transaction) DeserializeUnsigned(r io.Reader) error {
 	var txType [1]byte
 	_, err := io.ReadFull(r, txType[:])
@@ -228,7 +350,21 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 			tx.UTXOInputs = append(tx.UTXOInputs, utxo)
 		}
 	}
-	//TODO balanceInputs
+	//BalanceInputs
+	Len, err = serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return err
+	}
+	if Len > uint64(0) {
+		for i := uint64(0); i < Len; i++ {
+			balanceInput := new(BalanceTxInput)
+			err = balanceInput.Deserialize(r)
+			if err != nil {
+				return err
+			}
+			tx.BalanceInputs = append(tx.BalanceInputs, balanceInput)
+		}
+	}
 	//Outputs
 	Len, err = serialization.ReadVarUint(r, 0)
 	if err != nil {
@@ -254,7 +390,53 @@ func (tx *Transaction) DeserializeUnsignedWithoutType(r io.Reader) error {
 	return nil
 }
 
+// MarshalBinary implements the EIP-2718-style typed envelope: a typed
+// transaction (tx.inner != nil) is encoded as its type byte followed by
+// its type-specific payload, while a legacy transaction serializes
+// exactly as before.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if tx.inner != nil {
+		buf.WriteByte(tx.inner.txType())
+		if err := tx.inner.encode(&buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes data produced by MarshalBinary. It recognizes a
+// typed envelope by its leading byte; any other leading byte is treated as
+// an existing TransactionType and decoded along the legacy path.
+func (tx *Transaction) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("Transaction UnmarshalBinary: empty data")
+	}
+	if factory, ok := txInnerFactories[data[0]]; ok {
+		inner := factory()
+		if err := inner.decode(bytes.NewReader(data[1:])); err != nil {
+			return err
+		}
+		tx.inner = inner
+		tx.hash = nil
+		return nil
+	}
+	return tx.Deserialize(bytes.NewReader(data))
+}
+
 func (tx *Transaction) GetSize() int {
+	if tx.inner != nil {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return InvalidTransactionSize
+		}
+		return len(data)
+	}
+
 	var buffer bytes.Buffer
 	if err := tx.Serialize(&buffer); err != nil {
 		return InvalidTransactionSize
@@ -271,13 +453,37 @@ func (tx *Transaction) GetPrograms() []*program.Program {
 	return tx.Programs
 }
 
+// legacySignerHash is the double-SHA256 of SerializeUnsigned that
+// Transaction.Hash() used before Signer existed. It is exported to the
+// signer as LegacySigner.Hash so verification code can move onto the
+// Signer interface without Transaction.Hash()'s behavior changing under it.
+func legacySignerHash(tx *Transaction) Uint256 {
+	buf := new(bytes.Buffer)
+	tx.SerializeUnsigned(buf)
+	temp := sha256.Sum256(buf.Bytes())
+	return Uint256(sha256.Sum256(temp[:]))
+}
+
+// Hash hashes the canonical binary form of the transaction: the type byte
+// plus type-specific payload for a typed (tx.inner != nil) transaction, or
+// whichever Signer signerForTx selects for a legacy one (LegacySigner by
+// default, CrossChainSigner for cross-chain transactions).
 func (tx *Transaction) Hash() Uint256 {
+	if tx.Trimmed {
+		return *tx.hash
+	}
 	if tx.hash == nil {
-		buf := new(bytes.Buffer)
-		tx.SerializeUnsigned(buf)
-		temp := sha256.Sum256([]byte(buf.Bytes()))
-		f := Uint256(sha256.Sum256(temp[:]))
-		tx.hash = &f
+		if tx.inner != nil {
+			buf := new(bytes.Buffer)
+			buf.WriteByte(tx.inner.txType())
+			tx.inner.encode(buf)
+			temp := sha256.Sum256(buf.Bytes())
+			f := Uint256(sha256.Sum256(temp[:]))
+			tx.hash = &f
+		} else {
+			f := signerForTx(tx).Hash(tx)
+			tx.hash = &f
+		}
 	}
 	return *tx.hash
 }
@@ -286,12 +492,49 @@ func (tx *Transaction) IsCoinBaseTx() bool {
 	return tx.TxType == CoinBase
 }
 
+// TotalBalanceIn sums the Value of every BalanceInputs entry for assetID,
+// the account-model counterpart of summing UTXOInputs by referenced output.
+func (tx *Transaction) TotalBalanceIn(assetID Uint256) Fixed64 {
+	var total Fixed64
+	for _, balanceInput := range tx.BalanceInputs {
+		if balanceInput.AssetID == assetID {
+			total += balanceInput.Value
+		}
+	}
+	return total
+}
+
+// IsHybridInputTx reports whether tx spends both UTXOInputs and
+// account-model BalanceInputs, the hybrid shape the struct's parallel
+// input fields exist to support.
+func (tx *Transaction) IsHybridInputTx() bool {
+	return len(tx.UTXOInputs) > 0 && len(tx.BalanceInputs) > 0
+}
+
+// VerifyBalanceInputs rejects a CoinBase transaction that also carries
+// BalanceInputs: CoinBase mints new coin out of nothing, so it must not
+// also debit an account. A non-CoinBase transaction is free to mix
+// UTXOInputs and BalanceInputs (see IsHybridInputTx).
+func (tx *Transaction) VerifyBalanceInputs() error {
+	if tx.IsCoinBaseTx() && len(tx.BalanceInputs) > 0 {
+		return errors.New("VerifyBalanceInputs: CoinBase transaction must not carry BalanceInputs")
+	}
+	return nil
+}
+
 func (tx *Transaction) SetHash(hash Uint256) {
 	tx.hash = &hash
 }
 
 func (tx *Transaction) GetTransactionCode() ([]byte, error) {
-	code := tx.GetPrograms()[0].Code
+	if tx.Trimmed {
+		return nil, errors.New("invalid transaction type, trimmed transaction has no programs")
+	}
+	programs := tx.GetPrograms()
+	if len(programs) == 0 {
+		return nil, errors.New("invalid transaction type, redeem script not found")
+	}
+	code := programs[0].Code
 	if code == nil {
 		return nil, errors.New("invalid transaction type, redeem script not found")
 	}
@@ -327,6 +570,31 @@ func (tx *Transaction) GetMultiSignPublicKeys() ([][]byte, error) {
 	return publicKeys, nil
 }
 
+// SignatureScriptLength is one attached signature's encoding in a
+// program's Parameter: a push-length byte (0x40) followed by the 64-byte
+// signature.
+const SignatureScriptLength = 65
+
+// GetSignedCount returns how many signatures are actually attached to
+// tx.Programs[0].Parameter, as opposed to how many public keys its redeem
+// script names (see GetMultiSignPublicKeys) — the former can't be forged
+// by anyone who doesn't hold the corresponding private keys, the latter
+// can be copied from public information alone.
+func (tx *Transaction) GetSignedCount() (int, error) {
+	if tx.Trimmed {
+		return 0, errors.New("invalid transaction type, trimmed transaction has no programs")
+	}
+	programs := tx.GetPrograms()
+	if len(programs) == 0 {
+		return 0, errors.New("invalid transaction type, redeem script not found")
+	}
+	parameter := programs[0].Parameter
+	if len(parameter)%SignatureScriptLength != 0 {
+		return 0, errors.New("invalid transaction type, signature parameter length not match")
+	}
+	return len(parameter) / SignatureScriptLength, nil
+}
+
 func (tx *Transaction) GetTransactionType() (byte, error) {
 	code, err := tx.GetTransactionCode()
 	if err != nil {
@@ -0,0 +1,136 @@
+package transaction
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+func TestBalanceTxInputSerializeDeserialize(t *testing.T) {
+	input := &BalanceTxInput{
+		AssetID:     Uint256{1, 2, 3},
+		Value:       Fixed64(100),
+		ProgramHash: Uint168{4, 5, 6},
+		Nonce:       42,
+	}
+
+	buf := new(bytes.Buffer)
+	if err := input.Serialize(buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	result := new(BalanceTxInput)
+	if err := result.Deserialize(buf); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+
+	if result.AssetID != input.AssetID {
+		t.Errorf("AssetID mismatch: got %v, want %v", result.AssetID, input.AssetID)
+	}
+	if result.Value != input.Value {
+		t.Errorf("Value mismatch: got %v, want %v", result.Value, input.Value)
+	}
+	if result.ProgramHash != input.ProgramHash {
+		t.Errorf("ProgramHash mismatch: got %v, want %v", result.ProgramHash, input.ProgramHash)
+	}
+	if result.Nonce != input.Nonce {
+		t.Errorf("Nonce mismatch: got %v, want %v", result.Nonce, input.Nonce)
+	}
+}
+
+func TestTransactionTotalBalanceIn(t *testing.T) {
+	assetA := Uint256{1}
+	assetB := Uint256{2}
+	tx := &Transaction{
+		BalanceInputs: []*BalanceTxInput{
+			{AssetID: assetA, Value: 10},
+			{AssetID: assetA, Value: 5},
+			{AssetID: assetB, Value: 7},
+		},
+	}
+
+	if got := tx.TotalBalanceIn(assetA); got != Fixed64(15) {
+		t.Errorf("TotalBalanceIn(assetA) = %v, want 15", got)
+	}
+	if got := tx.TotalBalanceIn(assetB); got != Fixed64(7) {
+		t.Errorf("TotalBalanceIn(assetB) = %v, want 7", got)
+	}
+}
+
+func TestTransactionIsHybridInputTx(t *testing.T) {
+	utxoOnly := &Transaction{UTXOInputs: []*UTXOTxInput{{}}}
+	if utxoOnly.IsHybridInputTx() {
+		t.Errorf("IsHybridInputTx should be false with no BalanceInputs")
+	}
+
+	hybrid := &Transaction{
+		UTXOInputs:    []*UTXOTxInput{{}},
+		BalanceInputs: []*BalanceTxInput{{}},
+	}
+	if !hybrid.IsHybridInputTx() {
+		t.Errorf("IsHybridInputTx should be true when both UTXOInputs and BalanceInputs are present")
+	}
+}
+
+func TestTransactionVerifyBalanceInputs(t *testing.T) {
+	hybrid := &Transaction{
+		TxType:        CreateNFTTxType,
+		UTXOInputs:    []*UTXOTxInput{{}},
+		BalanceInputs: []*BalanceTxInput{{Value: 10}},
+	}
+	if err := hybrid.VerifyBalanceInputs(); err != nil {
+		t.Errorf("VerifyBalanceInputs should accept a non-CoinBase hybrid transaction: %v", err)
+	}
+
+	coinBaseWithBalanceInputs := &Transaction{
+		TxType:        CoinBase,
+		BalanceInputs: []*BalanceTxInput{{Value: 10}},
+	}
+	if err := coinBaseWithBalanceInputs.VerifyBalanceInputs(); err == nil {
+		t.Errorf("VerifyBalanceInputs should reject a CoinBase transaction carrying BalanceInputs")
+	}
+}
+
+// TestTransactionGetSizeAndHashWithBalanceInputs exercises GetSize and
+// Hash now that SerializeUnsigned/DeserializeUnsigned round-trip
+// BalanceInputs instead of skipping them.
+func TestTransactionGetSizeAndHashWithBalanceInputs(t *testing.T) {
+	tx := &Transaction{
+		TxType: CreateNFTTxType,
+		Payload: &CreateNFT{
+			ReferKey:         Uint256{7},
+			StakeAddress:     Uint168{8},
+			GenesisBlockHash: Uint256{9},
+		},
+		BalanceInputs: []*BalanceTxInput{
+			{AssetID: Uint256{9}, Value: 3, ProgramHash: Uint168{1}, Nonce: 1},
+		},
+	}
+
+	size := tx.GetSize()
+	if size <= 0 {
+		t.Fatalf("GetSize() = %d, want > 0", size)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tx.Serialize(buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	decoded := new(Transaction)
+	if err := decoded.Deserialize(buf); err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+
+	if len(decoded.BalanceInputs) != 1 {
+		t.Fatalf("BalanceInputs length = %d, want 1", len(decoded.BalanceInputs))
+	}
+	if decoded.BalanceInputs[0].Nonce != 1 {
+		t.Errorf("BalanceInputs[0].Nonce = %d, want 1", decoded.BalanceInputs[0].Nonce)
+	}
+
+	if tx.Hash() != decoded.Hash() {
+		t.Errorf("Hash mismatch after BalanceInputs round trip")
+	}
+}
@@ -0,0 +1,68 @@
+package transaction
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/elastos/Elastos.ELA.Utility/common"
+)
+
+func TestTransactionSerializeTrimmedRoundTrip(t *testing.T) {
+	full := &Transaction{
+		TxType: CreateNFTTxType,
+		Payload: &CreateNFT{
+			ReferKey:         Uint256{1},
+			StakeAddress:     Uint168{2},
+			GenesisBlockHash: Uint256{3},
+		},
+	}
+	hash := full.Hash()
+	spentOutputs := []bool{true, false, true}
+
+	buf := new(bytes.Buffer)
+	if err := full.SerializeTrimmed(buf, spentOutputs); err != nil {
+		t.Fatalf("SerializeTrimmed failed: %v", err)
+	}
+
+	trimmed := new(Transaction)
+	decodedSpent, err := trimmed.DeserializeTrimmed(buf)
+	if err != nil {
+		t.Fatalf("DeserializeTrimmed failed: %v", err)
+	}
+
+	if !trimmed.Trimmed {
+		t.Errorf("DeserializeTrimmed did not mark tx as Trimmed")
+	}
+	if trimmed.TxType != CreateNFTTxType {
+		t.Errorf("TxType = %v, want %v", trimmed.TxType, CreateNFTTxType)
+	}
+	if trimmed.Hash() != hash {
+		t.Errorf("Hash() = %v, want %v", trimmed.Hash(), hash)
+	}
+	if len(decodedSpent) != len(spentOutputs) {
+		t.Fatalf("spentOutputs length = %d, want %d", len(decodedSpent), len(spentOutputs))
+	}
+	for i := range spentOutputs {
+		if decodedSpent[i] != spentOutputs[i] {
+			t.Errorf("spentOutputs[%d] = %v, want %v", i, decodedSpent[i], spentOutputs[i])
+		}
+	}
+}
+
+func TestNewTrimmedTransactionHashShortCircuits(t *testing.T) {
+	hash := Uint256{9}
+	tx := NewTrimmedTransaction(hash)
+	if got := tx.Hash(); got != hash {
+		t.Errorf("Hash() = %v, want %v", got, hash)
+	}
+}
+
+func TestTrimmedTransactionGetTransactionCodeErrors(t *testing.T) {
+	tx := NewTrimmedTransaction(Uint256{1})
+	if _, err := tx.GetTransactionCode(); err == nil {
+		t.Errorf("GetTransactionCode on a trimmed tx should error rather than panic on a nil Programs slice")
+	}
+	if _, err := tx.GetMultiSignPublicKeys(); err == nil {
+		t.Errorf("GetMultiSignPublicKeys on a trimmed tx should error rather than panic")
+	}
+}